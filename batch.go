@@ -0,0 +1,193 @@
+package pwned
+
+import (
+	"bufio"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BatchResult pairs a password with the Result of checking it, returned by IsPwnedChan where results may
+// arrive in a different order than the passwords were sent.
+type BatchResult struct {
+	Password string
+	Result   Result
+	Err      error
+}
+
+// IsPwnedBatch synchronously checks many passwords at once, using the default client.
+func IsPwnedBatch(passwords []string) ([]Result, error) {
+	return defaultClient.IsPwnedBatch(passwords)
+}
+
+// IsPwnedBatch checks many passwords at once. Passwords are grouped by their SHA-1 range prefix so that each
+// unique prefix is fetched only once, regardless of how many input passwords share it, and those fetches are
+// spread across a pool of WithWorkerPoolSize workers. Results are returned in the same order as passwords.
+func (c *Client) IsPwnedBatch(passwords []string) ([]Result, error) {
+	hashes := make([]pwnedHash, len(passwords))
+	groups := map[string][]int{}
+	prefixes := make([]string, 0, len(passwords))
+	for i, password := range passwords {
+		if password == "" {
+			return nil, ErrEmptyPassword
+		}
+		hash := getHash(password)
+		hashes[i] = hash
+		if _, ok := groups[hash.Range]; !ok {
+			prefixes = append(prefixes, hash.Range)
+		}
+		groups[hash.Range] = append(groups[hash.Range], i)
+	}
+
+	results := make([]Result, len(passwords))
+	prefixCh := make(chan string)
+	errCh := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.workerCount(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prefix := range prefixCh {
+				entries, err := c.fetchRange(prefix, HashModeSHA1)
+				if err != nil {
+					select {
+					case errCh <- err:
+					default:
+					}
+					continue
+				}
+				for _, i := range groups[prefix] {
+					if count, ok := entries[hashes[i].Hash[len(hashes[i].Range):]]; ok {
+						results[i] = Result{Pwned: true, TimesObserved: count}
+					}
+				}
+			}
+		}()
+	}
+
+	for _, prefix := range prefixes {
+		prefixCh <- prefix
+	}
+	close(prefixCh)
+	wg.Wait()
+
+	select {
+	case err := <-errCh:
+		return nil, err
+	default:
+		return results, nil
+	}
+}
+
+// IsPwnedChan streams results using the default client.
+func IsPwnedChan(passwords <-chan string) <-chan BatchResult {
+	return defaultClient.IsPwnedChan(passwords)
+}
+
+// IsPwnedChan is the streaming counterpart to IsPwnedBatch, for checking passwords as they arrive rather than
+// all at once. It reads from passwords and emits a BatchResult for each over a pool of WithWorkerPoolSize
+// workers, closing the returned channel once passwords is closed and drained. Results may arrive out of order;
+// correlate them with BatchResult.Password. Enabling WithCache lets repeated or colliding prefixes across the
+// stream be served without a second request.
+func (c *Client) IsPwnedChan(passwords <-chan string) <-chan BatchResult {
+	out := make(chan BatchResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.workerCount(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for password := range passwords {
+				if password == "" {
+					out <- BatchResult{Password: password, Err: ErrEmptyPassword}
+					continue
+				}
+				hash := getHash(password)
+				entries, err := c.fetchRange(hash.Range, HashModeSHA1)
+				if err != nil {
+					out <- BatchResult{Password: password, Err: err}
+					continue
+				}
+				result := Result{}
+				if count, ok := entries[hash.Hash[len(hash.Range):]]; ok {
+					result = Result{Pwned: true, TimesObserved: count}
+				}
+				out <- BatchResult{Password: password, Result: result}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// workerCount returns the configured worker pool size, falling back to defaultWorkerPoolSize.
+func (c *Client) workerCount() int {
+	if c.workers <= 0 {
+		return defaultWorkerPoolSize
+	}
+	return c.workers
+}
+
+// fetchRange fetches and parses the full range response for prefix, returning every hash suffix in the range
+// mapped to its count. Unlike IsPwnedHash, which stops at the first match, this reads the whole range so that
+// multiple hashes sharing the same prefix can be checked against a single request. Results are served from
+// the client's cache, if configured, before falling back to the network.
+func (c *Client) fetchRange(prefix string, mode HashMode) (map[string]uint64, error) {
+	if c.cache != nil {
+		if entries, ok := c.cache.get(prefix, mode); ok {
+			return entries, nil
+		}
+	}
+
+	url := c.baseURL + "/range/" + prefix
+	if mode == HashModeNTLM {
+		url += "?mode=ntlm"
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.padding {
+		req.Header.Set("Add-Padding", "true")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	entries := map[string]uint64{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		components := strings.Split(scanner.Text(), ":")
+		if len(components) != 2 {
+			continue
+		}
+		count, err := strconv.ParseUint(components[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			// A count of 0 indicates a dummy entry added by the Add-Padding header, not a real result.
+			continue
+		}
+		entries[components[0]] = count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		c.cache.set(prefix, mode, entries)
+	}
+	return entries, nil
+}
@@ -0,0 +1,89 @@
+package pwned
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// rangeCache is an in-memory, size-bounded, TTL-expiring cache of parsed range API responses, keyed by hash
+// prefix and mode. It exists so that IsPwnedBatch and IsPwnedChan don't re-fetch the same range when a batch
+// contains passwords that share a prefix, or when the same passwords are checked again across calls.
+type rangeCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   list.List
+	entries map[string]*list.Element
+}
+
+type rangeCacheEntry struct {
+	key       string
+	hashes    map[string]uint64
+	expiresAt time.Time
+}
+
+func newRangeCache(size int, ttl time.Duration) *rangeCache {
+	return &rangeCache{
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+	}
+}
+
+func rangeCacheKey(prefix string, mode HashMode) string {
+	return string(mode) + ":" + prefix
+}
+
+// get returns the cached hash:count entries for prefix and mode, if present and not yet expired.
+func (r *rangeCache) get(prefix string, mode HashMode) (map[string]uint64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := rangeCacheKey(prefix, mode)
+	el, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*rangeCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		r.order.Remove(el)
+		delete(r.entries, key)
+		return nil, false
+	}
+
+	r.order.MoveToFront(el)
+	return entry.hashes, true
+}
+
+// set stores hashes for prefix and mode, evicting the least recently used entry if the cache is full.
+func (r *rangeCache) set(prefix string, mode HashMode, hashes map[string]uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := rangeCacheKey(prefix, mode)
+	if el, ok := r.entries[key]; ok {
+		entry := el.Value.(*rangeCacheEntry)
+		entry.hashes = hashes
+		entry.expiresAt = time.Now().Add(r.ttl)
+		r.order.MoveToFront(el)
+		return
+	}
+
+	el := r.order.PushFront(&rangeCacheEntry{
+		key:       key,
+		hashes:    hashes,
+		expiresAt: time.Now().Add(r.ttl),
+	})
+	r.entries[key] = el
+
+	for r.size > 0 && r.order.Len() > r.size {
+		oldest := r.order.Back()
+		if oldest == nil {
+			break
+		}
+		r.order.Remove(oldest)
+		delete(r.entries, oldest.Value.(*rangeCacheEntry).key)
+	}
+}
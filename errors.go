@@ -0,0 +1,33 @@
+package pwned
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrEmptyPassword is returned by IsPwned when given an empty password.
+var ErrEmptyPassword = errors.New("pwned: password must not be empty")
+
+// ErrRateLimited is returned when the API responds 429 Too Many Requests and retries are exhausted.
+// RetryAfter holds the duration from the response's Retry-After header, if one was present.
+type ErrRateLimited struct {
+	RetryAfter time.Duration
+}
+
+func (e *ErrRateLimited) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("pwned: rate limited, retry after %s", e.RetryAfter)
+	}
+	return "pwned: rate limited"
+}
+
+// ErrUnexpectedStatus is returned when the API responds with a status code other than 200 OK, after retries
+// for 429/5xx responses are exhausted.
+type ErrUnexpectedStatus struct {
+	Code int
+}
+
+func (e *ErrUnexpectedStatus) Error() string {
+	return fmt.Sprintf("pwned: unexpected status code %d", e.Code)
+}
@@ -0,0 +1,229 @@
+package pwned
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// offlineMagic identifies a file previously written by Import. Files without this header are assumed to be a
+// raw "hash:count" dump of full 40 character hashes, as published by the official Pwned Passwords downloader.
+// Bare range API suffix lines never match a lookup.
+const offlineMagic = "PWNOFFV1"
+
+// offlineRecordSize the size in bytes of one record in the on-disk format: a 20 byte raw SHA-1 hash followed
+// by an 8 byte big-endian count.
+const offlineRecordSize = sha1.Size + 8
+
+// OfflineChecker answers IsPwned queries from a local copy of the HIBP Pwned Passwords dump instead of the
+// network. Construct one with NewOfflineChecker.
+//
+// For best performance, first convert the dump to the package's compact on-disk format with Import; this lets
+// OfflineChecker binary search the file in O(log n) without loading it into memory. If given a raw,
+// unconverted dump, OfflineChecker falls back to a linear scan per lookup.
+type OfflineChecker struct {
+	file    *os.File
+	indexed bool
+	count   int64
+}
+
+// NewOfflineChecker opens the password dump at path for querying. path may point either to a database
+// previously written by Import, or to a raw "hash:count" dump, in which case lookups fall back to a linear
+// scan of the file.
+func NewOfflineChecker(path string) (*OfflineChecker, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, len(offlineMagic))
+	n, err := io.ReadFull(f, header)
+	if err == nil && n == len(header) && string(header) == offlineMagic {
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		dataSize := info.Size() - int64(len(offlineMagic))
+		if dataSize%offlineRecordSize != 0 {
+			f.Close()
+			return nil, errors.New("pwned: not a valid offline database: truncated record")
+		}
+		return &OfflineChecker{
+			file:    f,
+			indexed: true,
+			count:   dataSize / offlineRecordSize,
+		}, nil
+	}
+
+	// Not one of ours: treat it as a raw dump (sorted-by-hash or ordered-by-prevalence) and scan it on
+	// every lookup instead. Callers that need faster repeated lookups against a raw dump should run it
+	// through Import once to build an indexed database.
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &OfflineChecker{file: f, indexed: false}, nil
+}
+
+// Close closes the underlying file. It should be called once the OfflineChecker is no longer needed.
+func (o *OfflineChecker) Close() error {
+	return o.file.Close()
+}
+
+// IsPwned synchronously check if the provided password has been pwned, answered entirely from the local database.
+func (o *OfflineChecker) IsPwned(password string) (*Result, error) {
+	hash := getHash(password)
+	raw, err := hex.DecodeString(hash.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.indexed {
+		return o.lookupIndexed(raw)
+	}
+	return o.lookupScan(raw)
+}
+
+// lookupIndexed binary searches the fixed-width record file written by Import.
+func (o *OfflineChecker) lookupIndexed(hash []byte) (*Result, error) {
+	record := make([]byte, offlineRecordSize)
+
+	lo, hi := int64(0), o.count-1
+	for lo <= hi {
+		mid := lo + (hi-lo)/2
+		offset := int64(len(offlineMagic)) + mid*offlineRecordSize
+		if _, err := o.file.ReadAt(record, offset); err != nil {
+			return nil, err
+		}
+
+		switch compareHash(record[:sha1.Size], hash) {
+		case 0:
+			count := binary.BigEndian.Uint64(record[sha1.Size:])
+			return &Result{Pwned: true, TimesObserved: count}, nil
+		case -1:
+			lo = mid + 1
+		default:
+			hi = mid - 1
+		}
+	}
+
+	return &Result{Pwned: false, TimesObserved: 0}, nil
+}
+
+// lookupScan linearly scans a raw "hash:count" dump, for use when no index is available.
+func (o *OfflineChecker) lookupScan(hash []byte) (*Result, error) {
+	target := strings.ToUpper(hex.EncodeToString(hash))
+
+	if _, err := o.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(o.file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		idx := strings.IndexByte(line, ':')
+		if idx < 0 {
+			continue
+		}
+		if !strings.EqualFold(line[:idx], target) {
+			continue
+		}
+		count, err := strconv.ParseUint(line[idx+1:], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Pwned: true, TimesObserved: count}, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Result{Pwned: false, TimesObserved: 0}, nil
+}
+
+// compareHash compares two equal-length raw hashes, returning -1, 0, or 1.
+func compareHash(a, b []byte) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Import reads newline-delimited "hash:count" records from r, where hash is the full 40 character hex SHA-1,
+// and writes them to path in the package's compact, binary-searchable on-disk format for use with
+// NewOfflineChecker. The input must already be sorted by hash, as the official Pwned Passwords downloader's
+// ordered-by-hash dump is; ordered-by-prevalence dumps must be sorted by hash first, for example with `sort`,
+// before being passed to Import.
+//
+// The range API only returns the 35 character suffix of each hash, not the full hash, so a concatenation of
+// range API responses cannot be passed to Import directly: the queried prefix must first be prepended back
+// onto each suffix line.
+func Import(r io.Reader, path string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w := bufio.NewWriter(out)
+	if _, err := w.WriteString(offlineMagic); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	record := make([]byte, offlineRecordSize)
+	var previous []byte
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		idx := strings.IndexByte(line, ':')
+		if idx < 0 {
+			return fmt.Errorf("pwned: malformed import line %q", line)
+		}
+
+		if len(line[:idx]) != sha1.Size*2 {
+			return fmt.Errorf("pwned: expected a full %d character hash but got %d characters for %q; "+
+				"a bare range API suffix must have its queried prefix prepended before being imported",
+				sha1.Size*2, len(line[:idx]), line[:idx])
+		}
+		raw, err := hex.DecodeString(line[:idx])
+		if err != nil {
+			return fmt.Errorf("pwned: malformed hash %q: %w", line[:idx], err)
+		}
+		count, err := strconv.ParseUint(line[idx+1:], 10, 64)
+		if err != nil {
+			return fmt.Errorf("pwned: malformed count %q: %w", line[idx+1:], err)
+		}
+		if previous != nil && compareHash(raw, previous) < 0 {
+			return fmt.Errorf("pwned: import input is not sorted by hash at %q", line[:idx])
+		}
+		previous = append(previous[:0], raw...)
+
+		copy(record, raw)
+		binary.BigEndian.PutUint64(record[sha1.Size:], count)
+		if _, err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
@@ -4,15 +4,20 @@
 package pwned
 
 import (
+	"bufio"
+	"context"
 	"crypto/sha1"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// defaultBaseURL the base URL of the Pwned Passwords range API used when no WithBaseURL option is given.
+const defaultBaseURL = "https://api.pwnedpasswords.com"
+
 // Result describes a result from the Pwned Password service.
 type Result struct {
 	// Pwned has the password been seen at least once. A value of value doesn't mean the password is any good though.
@@ -26,6 +31,116 @@ type pwnedHash struct {
 	Range string
 }
 
+// HashMode selects the hash algorithm a password (or pre-computed hash) is checked under. HIBP publishes
+// Pwned Passwords under both formats at the same range endpoint, selected by a `mode` query parameter.
+type HashMode string
+
+const (
+	// HashModeSHA1 checks against SHA-1 hashes, the default used by the public Pwned Passwords service.
+	HashModeSHA1 HashMode = "sha1"
+	// HashModeNTLM checks against NTLM hashes, as used by Active Directory and other Windows systems. Pass
+	// an already-computed NTLM hash to IsPwnedHash; this package does not compute NTLM hashes itself.
+	HashModeNTLM HashMode = "ntlm"
+)
+
+// Checker can answer whether a password has been seen in a known data breach. Client queries the online HIBP
+// API, while OfflineChecker answers from a local copy of the Pwned Passwords dump. Both implement Checker so
+// callers can swap between them without changing call sites.
+type Checker interface {
+	IsPwned(password string) (*Result, error)
+}
+
+var (
+	_ Checker = (*Client)(nil)
+	_ Checker = (*OfflineChecker)(nil)
+)
+
+// Client is a configurable client for the Pwned Passwords API. Use NewClient to construct one, or use the
+// package-level IsPwned/IsPwnedAsync functions which operate against a default client.
+type Client struct {
+	httpClient *http.Client
+	ctx        context.Context
+	baseURL    string
+	padding    bool
+	workers    int
+	cache      *rangeCache
+}
+
+// defaultWorkerPoolSize the number of concurrent range requests IsPwnedBatch and IsPwnedChan issue when
+// WithWorkerPoolSize is not given.
+const defaultWorkerPoolSize = 8
+
+// Option configures a Client. See WithHTTPClient, WithContext, WithBaseURL, WithPadding, WithWorkerPoolSize,
+// and WithCache.
+type Option func(*Client)
+
+// WithHTTPClient sets the *http.Client used to make requests. If not specified http.DefaultClient is used.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithContext sets the context.Context used for requests, allowing callers to apply timeouts or cancellation.
+// If not specified context.Background() is used.
+func WithContext(ctx context.Context) Option {
+	return func(c *Client) {
+		c.ctx = ctx
+	}
+}
+
+// WithBaseURL overrides the base URL of the Pwned Passwords API. Useful for pointing at a private mirror or
+// a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithPadding enables the Add-Padding request header, which instructs the API to pad the range response with
+// dummy hash:count entries. This hides the true number of results for the requested prefix from anyone
+// observing the response size on the network. See https://haveibeenpwned.com/API/v3#PwnedPasswordsPadding
+func WithPadding() Option {
+	return func(c *Client) {
+		c.padding = true
+	}
+}
+
+// WithWorkerPoolSize sets the number of concurrent range requests IsPwnedBatch and IsPwnedChan will issue.
+// The default is 8.
+func WithWorkerPoolSize(n int) Option {
+	return func(c *Client) {
+		c.workers = n
+	}
+}
+
+// WithCache enables an in-memory LRU cache of range responses, shared across IsPwnedBatch and IsPwnedChan
+// lookups, holding up to size prefixes before the oldest is evicted. A cached prefix is re-fetched once it is
+// older than ttl. This avoids re-fetching the same range when a batch contains passwords that share a prefix,
+// or when the same passwords are checked again across calls.
+func WithCache(size int, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = newRangeCache(size, ttl)
+	}
+}
+
+// NewClient creates a new Client with the given options applied.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		ctx:        context.Background(),
+		baseURL:    defaultBaseURL,
+		workers:    defaultWorkerPoolSize,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// defaultClient the client used by the package-level IsPwned and IsPwnedAsync functions.
+var defaultClient = NewClient()
+
 // IsPwnedAsync asynchronously check if the provided password has been pwned. Calls `cb` with the result when finished.
 func IsPwnedAsync(password string, cb func(*Result, error)) {
 	go func() {
@@ -33,31 +148,78 @@ func IsPwnedAsync(password string, cb func(*Result, error)) {
 	}()
 }
 
-// IsPwned synchronously check if the provided password has been pwned.
+// IsPwned synchronously check if the provided password has been pwned, using the default client.
 func IsPwned(password string) (*Result, error) {
+	return defaultClient.IsPwned(password)
+}
+
+// IsPwned synchronously check if the provided password has been pwned.
+func (c *Client) IsPwned(password string) (*Result, error) {
+	if password == "" {
+		return nil, ErrEmptyPassword
+	}
 	hash := getHash(password)
-	resp, err := http.Get("https://api.pwnedpasswords.com/range/" + hash.Range)
+	return c.IsPwnedHash(hash.Hash, HashModeSHA1)
+}
+
+// IsPwnedHash synchronously check if the given pre-computed hash has been pwned, using the default client.
+// hash must be hex-encoded (case-insensitive) and mode must identify the algorithm used to produce it.
+func IsPwnedHash(hash string, mode HashMode) (*Result, error) {
+	return defaultClient.IsPwnedHash(hash, mode)
+}
+
+// IsPwnedHash synchronously check if the given pre-computed hash has been pwned. hash must be hex-encoded
+// (case-insensitive) and mode must identify the algorithm used to produce it. This is useful for callers that
+// already have a hash on hand, such as NTLM hashes pulled from Active Directory, and want to avoid
+// re-deriving it from plaintext.
+func (c *Client) IsPwnedHash(hash string, mode HashMode) (*Result, error) {
+	if hash == "" {
+		return nil, ErrEmptyPassword
+	}
+	hash = strings.ToUpper(hash)
+	if len(hash) < 5 {
+		return nil, fmt.Errorf("pwned: hash %q is too short", hash)
+	}
+	hashRange := hash[0:5]
+
+	url := c.baseURL + "/range/" + hashRange
+	if mode == HashModeNTLM {
+		url += "?mode=ntlm"
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
+	if c.padding {
+		req.Header.Set("Add-Padding", "true")
+	}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
-	lines := strings.Split(string(body), "\r\n")
-	for _, line := range lines {
-		components := strings.Split(line, ":")
+
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		components := strings.Split(scanner.Text(), ":")
+		if len(components) != 2 {
+			continue
+		}
 		resultHash := components[0]
 		countStr := components[1]
 
-		if hash.Range+resultHash == hash.Hash {
-			count, err := strconv.ParseUint(countStr, 10, 64)
-			if err != nil {
-				return nil, err
-			}
+		count, err := strconv.ParseUint(countStr, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			// A count of 0 indicates a dummy entry added by the Add-Padding header, not a real result.
+			continue
+		}
 
+		if hashRange+resultHash == hash {
 			ret := Result{
 				Pwned:         true,
 				TimesObserved: count,
@@ -65,6 +227,9 @@ func IsPwned(password string) (*Result, error) {
 			return &ret, nil
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
 
 	ret := Result{
 		Pwned:         false,
@@ -82,4 +247,4 @@ func getHash(password string) pwnedHash {
 		Hash:  hash,
 		Range: hash[0:5],
 	}
-}
\ No newline at end of file
+}
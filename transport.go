@@ -0,0 +1,77 @@
+package pwned
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetries the number of additional attempts do makes after a 429 or 5xx response before giving up.
+const maxRetries = 3
+
+// baseRetryDelay the backoff used for the first retry when the API did not send a Retry-After header; each
+// subsequent attempt doubles it.
+const baseRetryDelay = 500 * time.Millisecond
+
+// do executes req, retrying on 429 and 5xx responses with exponential backoff, honoring a Retry-After header
+// when the API sends one, up to maxRetries times. On success it returns the response with status 200 OK; the
+// caller is responsible for closing the body. A response that never reaches 200, after retries, is translated
+// into an *ErrRateLimited or *ErrUnexpectedStatus instead of being returned to the caller, since the pwned
+// package has no use for a body it can't trust to contain hash:count lines.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(retryDelay(attempt, lastErr)):
+			case <-c.ctx.Done():
+				return nil, c.ctx.Err()
+			}
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			lastErr = &ErrRateLimited{RetryAfter: retryAfter(resp)}
+			continue
+		}
+		if resp.StatusCode >= 500 {
+			lastErr = &ErrUnexpectedStatus{Code: resp.StatusCode}
+			continue
+		}
+		return nil, &ErrUnexpectedStatus{Code: resp.StatusCode}
+	}
+	return nil, lastErr
+}
+
+// retryDelay returns how long to wait before the given retry attempt (1-indexed), preferring the API's
+// requested Retry-After delay, if any, over our own exponential backoff.
+func retryDelay(attempt int, lastErr error) time.Duration {
+	if rl, ok := lastErr.(*ErrRateLimited); ok && rl.RetryAfter > 0 {
+		return rl.RetryAfter
+	}
+	return baseRetryDelay * time.Duration(1<<(attempt-1))
+}
+
+// retryAfter parses the Retry-After header, which may be either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}